@@ -0,0 +1,178 @@
+/* libguestfs Go bindings
+ * Copyright (C) 2013 Red Hat Inc.
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA
+ */
+
+package guestfs
+
+import (
+	"context"
+)
+
+/* ContextGuestfs binds a handle to a context.Context.  Every call made
+ * through it is cancelled as soon as the context is done, instead of
+ * blocking until the underlying libguestfs operation finishes.  Only
+ * Launch, Shutdown, Mount, Upload and Download have context-aware forms
+ * so far; see the package comment.
+ */
+type ContextGuestfs struct {
+	g   *Guestfs
+	ctx context.Context
+}
+
+/* WithContext returns a view of g on which blocking calls abort when
+ * ctx is cancelled, instead of running to completion.  It does not
+ * start any operation itself; it just arranges for later calls to
+ * race the context against the call.
+ */
+func (g *Guestfs) WithContext (ctx context.Context) *ContextGuestfs {
+	return &ContextGuestfs{g: g, ctx: ctx}
+}
+
+/* run starts fn in its own goroutine and waits for either fn to
+ * return, or ctx to be cancelled.  In the latter case it asks
+ * libguestfs to abort the in-flight call via guestfs_user_cancel, then
+ * waits for fn to actually return before handing control back to the
+ * caller, so that the handle is never used concurrently from two
+ * goroutines.  guestfs_user_cancel is only documented as safe to call
+ * while a FileIn/FileOut transfer is in progress, so run must only be
+ * used to wrap calls like Upload and Download.
+ */
+func (c *ContextGuestfs) run (fn func () error) error {
+	if err := c.ctx.Err (); err != nil {
+		return err
+	}
+
+	done := make (chan error, 1)
+	go func () {
+		done <- fn ()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-c.ctx.Done ():
+		c.g.User_cancel ()
+		<-done
+		return c.ctx.Err ()
+	}
+}
+
+/* waitThenCheck starts fn in its own goroutine, waits for it to
+ * return, and then reports ctx.Err() in preference to fn's own result
+ * if ctx was cancelled in the meantime.  Unlike run, it never touches
+ * the handle from the goroutine that is racing the context: it is used
+ * for calls like Launch and Shutdown, where there is no API documented
+ * safe to call concurrently with an in-flight operation, so they
+ * cannot actually be aborted early, only reported as cancelled once
+ * they finish.
+ */
+func (c *ContextGuestfs) waitThenCheck (fn func () error) error {
+	if err := c.ctx.Err (); err != nil {
+		return err
+	}
+
+	done := make (chan error, 1)
+	go func () {
+		done <- fn ()
+	}()
+
+	err := <-done
+	if ctxErr := c.ctx.Err (); ctxErr != nil {
+		return ctxErr
+	}
+	return err
+}
+
+/* Launch is like Launch, but reports ctx.Err() instead of Launch's own
+ * result if ctx is cancelled before Launch returns.  There is no
+ * libguestfs API documented safe to call concurrently with an
+ * in-flight Launch (only guestfs_user_cancel is, and it only affects
+ * file transfers), so a cancelled Launch still runs to completion in
+ * the background; it is reported as cancelled, not aborted early.
+ */
+func (c *ContextGuestfs) Launch () error {
+	return c.waitThenCheck (func () error {
+		return c.g.Launch ()
+	})
+}
+
+/* Shutdown is like Shutdown, but reports ctx.Err() instead of
+ * Shutdown's own result if ctx is cancelled before Shutdown returns.
+ * As with Launch, guestfs_user_cancel has no effect on Shutdown, so
+ * this cannot abort an in-flight Shutdown early, only report it as
+ * cancelled once it finishes.
+ */
+func (c *ContextGuestfs) Shutdown () error {
+	return c.waitThenCheck (func () error {
+		return c.g.Shutdown ()
+	})
+}
+
+/* Mount is like Mount, but reports ctx.Err() instead of Mount's own
+ * result if ctx is cancelled before Mount returns.  As with Launch and
+ * Shutdown, there is no libguestfs API documented safe to call
+ * concurrently with an in-flight Mount, so this cannot abort it early,
+ * only report it as cancelled once it finishes.
+ */
+func (c *ContextGuestfs) Mount (device string, mountpoint string) error {
+	return c.waitThenCheck (func () error {
+		return c.g.Mount (device, mountpoint)
+	})
+}
+
+/* Upload is like Upload, but aborted via guestfs_user_cancel if ctx is
+ * cancelled before the transfer completes.
+ */
+func (c *ContextGuestfs) Upload (localpath string, remotefilename string) error {
+	return c.run (func () error {
+		return c.g.Upload (localpath, remotefilename)
+	})
+}
+
+/* Download is like Download, but aborted via guestfs_user_cancel if
+ * ctx is cancelled before the transfer completes.
+ */
+func (c *ContextGuestfs) Download (remotefilename string, localpath string) error {
+	return c.run (func () error {
+		return c.g.Download (remotefilename, localpath)
+	})
+}
+
+/* MountContext is a shorthand for g.WithContext(ctx).Mount(...). */
+func (g *Guestfs) MountContext (ctx context.Context, device string, mountpoint string) error {
+	return g.WithContext (ctx).Mount (device, mountpoint)
+}
+
+/* LaunchContext is a shorthand for g.WithContext(ctx).Launch(). */
+func (g *Guestfs) LaunchContext (ctx context.Context) error {
+	return g.WithContext (ctx).Launch ()
+}
+
+/* ShutdownContext is a shorthand for g.WithContext(ctx).Shutdown(). */
+func (g *Guestfs) ShutdownContext (ctx context.Context) error {
+	return g.WithContext (ctx).Shutdown ()
+}
+
+/* UploadContext is a shorthand for g.WithContext(ctx).Upload(...). */
+func (g *Guestfs) UploadContext (ctx context.Context, localpath string, remotefilename string) error {
+	return g.WithContext (ctx).Upload (localpath, remotefilename)
+}
+
+/* DownloadContext is a shorthand for g.WithContext(ctx).Download(...). */
+func (g *Guestfs) DownloadContext (ctx context.Context, remotefilename string, localpath string) error {
+	return g.WithContext (ctx).Download (remotefilename, localpath)
+}
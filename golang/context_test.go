@@ -0,0 +1,98 @@
+/* libguestfs Go bindings
+ * Copyright (C) 2013 Red Hat Inc.
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA
+ */
+
+package guestfs
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+/* Neither test below lets ctx be cancelled while fn is still running
+ * from inside run(): that path calls c.g.User_cancel(), which needs a
+ * real handle.  Both tests instead exercise the parts of run and
+ * waitThenCheck that only ever touch ctx, which is all either helper
+ * needs from c.g-less cases.
+ */
+
+func TestRunReturnsCtxErrWithoutRunningFnIfAlreadyCancelled (t *testing.T) {
+	ctx, cancel := context.WithCancel (context.Background ())
+	cancel ()
+
+	c := &ContextGuestfs{ctx: ctx}
+	called := false
+	err := c.run (func () error {
+		called = true
+		return nil
+	})
+
+	if err != context.Canceled {
+		t.Errorf ("run () = %v, want context.Canceled", err)
+	}
+	if called {
+		t.Errorf ("run () called fn even though ctx was already cancelled")
+	}
+}
+
+func TestWaitThenCheckReturnsCtxErrWithoutRunningFnIfAlreadyCancelled (t *testing.T) {
+	ctx, cancel := context.WithCancel (context.Background ())
+	cancel ()
+
+	c := &ContextGuestfs{ctx: ctx}
+	called := false
+	err := c.waitThenCheck (func () error {
+		called = true
+		return nil
+	})
+
+	if err != context.Canceled {
+		t.Errorf ("waitThenCheck () = %v, want context.Canceled", err)
+	}
+	if called {
+		t.Errorf ("waitThenCheck () called fn even though ctx was already cancelled")
+	}
+}
+
+func TestWaitThenCheckReportsCtxErrOverFnResultIfCancelledDuringFn (t *testing.T) {
+	ctx, cancel := context.WithCancel (context.Background ())
+	c := &ContextGuestfs{ctx: ctx}
+
+	fnErr := errors.New ("fn failed")
+	err := c.waitThenCheck (func () error {
+		cancel ()
+		return fnErr
+	})
+
+	if err != context.Canceled {
+		t.Errorf ("waitThenCheck () = %v, want context.Canceled", err)
+	}
+}
+
+func TestWaitThenCheckReturnsFnResultIfNotCancelled (t *testing.T) {
+	c := &ContextGuestfs{ctx: context.Background ()}
+
+	fnErr := errors.New ("fn failed")
+	err := c.waitThenCheck (func () error {
+		return fnErr
+	})
+
+	if err != fnErr {
+		t.Errorf ("waitThenCheck () = %v, want %v", err, fnErr)
+	}
+}
@@ -0,0 +1,27 @@
+/* libguestfs Go bindings
+ * Copyright (C) 2013 Red Hat Inc.
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA
+ */
+
+/* Package guestfs is the libguestfs Go bindings.  Guestfs, GuestfsError
+ * and the Optargs* structs, and one method per libguestfs API, are
+ * generated by generator/golang.ml and are not part of this tree; the
+ * files here (context.go, options.go, transfer.go, inspect.go,
+ * events.go) are hand-written companions to that generated code and
+ * have not been built against it. Each says where it is standing in
+ * for, or waiting on, the generator.
+ */
+package guestfs
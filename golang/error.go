@@ -0,0 +1,29 @@
+/* libguestfs Go bindings
+ * Copyright (C) 2013 Red Hat Inc.
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA
+ */
+
+package guestfs
+
+/* Error makes *GuestfsError satisfy the standard error interface, on
+ * top of its existing String() method, so that handle methods' return
+ * values can be used directly anywhere plain error is expected (e.g.
+ * sent down a chan error, or returned from a func() error closure),
+ * instead of every caller having to convert by hand.
+ */
+func (e *GuestfsError) Error () string {
+	return e.String ()
+}
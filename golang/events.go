@@ -0,0 +1,91 @@
+/* libguestfs Go bindings
+ * Copyright (C) 2013 Red Hat Inc.
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA
+ */
+
+package guestfs
+
+/* EventMask is a bitmask of EVENT_* constants, as passed to
+ * Set_event_callback.
+ */
+type EventMask uint64
+
+/* Event is one callback invocation from libguestfs: either a progress
+ * update (EVENT_PROGRESS) or a log line (EVENT_APPLIANCE,
+ * EVENT_LIBRARY, EVENT_TRACE, EVENT_WARNING, ...).  Buf and Array mean
+ * different things depending on Type; see the libguestfs(3) "EVENTS"
+ * section.
+ */
+type Event struct {
+	Type  uint64
+	Buf   []byte
+	Array []uint64
+}
+
+/* Position and Total decode the two counters carried by an
+ * EVENT_PROGRESS event (array[2] and array[3]; array[0] and array[1]
+ * are the procedure number and a serial number, and are not exposed
+ * here).  Calling it on any other event type will panic.
+ */
+func (e Event) Position () uint64 { return e.Array[2] }
+func (e Event) Total () uint64    { return e.Array[3] }
+
+/* Subscribe registers a callback for every event in mask, and returns
+ * a channel that receives them plus a cancel function that unhooks
+ * the callback.  The caller must call cancel once it is no longer
+ * interested in the events, to free the callback; it does not need to
+ * keep draining the channel afterwards.
+ *
+ * If the channel's buffer fills up because nothing is reading from
+ * it, further events are dropped rather than blocking the libguestfs
+ * event dispatch thread.
+ *
+ * cancel does not close the channel: a call already in flight on
+ * another goroutine may still deliver an event after
+ * Delete_event_callback returns, and closing the channel underneath
+ * that send would panic.  The channel is simply left for the garbage
+ * collector once nothing refers to it any more.
+ */
+func (g *Guestfs) Subscribe (mask EventMask) (<-chan Event, func (), error) {
+	ch := make (chan Event, 64)
+
+	eh, err := g.Set_event_callback (
+		func (event uint64, eventHandle int, buf []byte, array []uint64) {
+			trySend (ch, Event{Type: event, Buf: buf, Array: array})
+		},
+		uint64 (mask))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cancel := func () {
+		g.Delete_event_callback (eh)
+	}
+
+	return ch, cancel, nil
+}
+
+/* trySend delivers e to ch without blocking: if ch's buffer is full,
+ * the event is dropped.  It never closes ch, so it is always safe to
+ * call even after cancel has unregistered the callback: a call already
+ * in flight on another goroutine may still land here.
+ */
+func trySend (ch chan Event, e Event) {
+	select {
+	case ch <- e:
+	default:
+	}
+}
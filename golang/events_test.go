@@ -0,0 +1,66 @@
+/* libguestfs Go bindings
+ * Copyright (C) 2013 Red Hat Inc.
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA
+ */
+
+package guestfs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTrySendDropsRatherThanBlocksWhenChannelIsFull (t *testing.T) {
+	ch := make (chan Event, 1)
+	ch <- Event{Type: 1}
+
+	done := make (chan struct{})
+	go func () {
+		trySend (ch, Event{Type: 2})
+		close (done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After (time.Second):
+		t.Fatal ("trySend blocked instead of dropping the event")
+	}
+
+	if got := <-ch; got.Type != 1 {
+		t.Errorf ("channel held %v, want the original event to survive", got)
+	}
+	select {
+	case got := <-ch:
+		t.Errorf ("channel held a second event %v, want it dropped", got)
+	default:
+	}
+}
+
+func TestTrySendAfterCancelDoesNotPanic (t *testing.T) {
+	/* cancel only unregisters the libguestfs callback; it never
+	 * closes the channel, because a call already in flight on
+	 * another goroutine could still land here.  This reproduces
+	 * that "in flight after cancel" send directly.
+	 */
+	ch := make (chan Event, 1)
+
+	defer func () {
+		if r := recover (); r != nil {
+			t.Errorf ("trySend panicked on a send after cancel: %v", r)
+		}
+	}()
+	trySend (ch, Event{Type: 1})
+}
@@ -35,31 +35,25 @@ func main() {
 		panic (err)
 	}
 
-	/* Ask libguestfs to inspect for operating systems. */
-	roots, err := g.Inspect_os ()
+	/* Ask libguestfs to inspect for operating systems, gathering the
+	 * details we care about into one value per root. */
+	oses, err := g.InspectAll ()
 	if err != nil {
 		panic (err)
 	}
-	if len(roots) == 0 {
+	if len (oses) == 0 {
 		panic ("inspect-vm: no operating systems found")
 	}
 
-	for _, root := range roots {
-		fmt.Printf ("Root device: %s\n", root)
+	for _, o := range oses {
+		fmt.Printf ("Root device: %s\n", o.Root)
+		fmt.Printf ("  Product name: %s\n", o.ProductName)
+		fmt.Printf ("  Version:      %d.%d\n", o.MajorVersion, o.MinorVersion)
+		fmt.Printf ("  Type:         %s\n", o.Type)
+		fmt.Printf ("  Distro:       %s\n", o.Distro)
 
-		/* Print basic information about the operating system. */
-		s, _ := g.Inspect_get_product_name (root)
-		fmt.Printf ("  Product name: %s\n", s)
-		major, _ := g.Inspect_get_major_version (root)
-		minor, _ := g.Inspect_get_minor_version (root)
-		fmt.Printf ("  Version:      %d.%d\n", major, minor)
-		s, _ = g.Inspect_get_type (root)
-		fmt.Printf ("  Type:         %s\n", s)
-		s, _ = g.Inspect_get_distro (root)
-		fmt.Printf ("  Distro:       %s\n", s)
-
-		/* XXX Incomplete example.  Sorting the keys by length
-		 * is unnecessarily hard in golang.
-		 */
+		if err := o.MountAll (g); err != nil {
+			panic (err)
+		}
 	}
 }
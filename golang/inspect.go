@@ -0,0 +1,126 @@
+/* libguestfs Go bindings
+ * Copyright (C) 2013 Red Hat Inc.
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA
+ */
+
+package guestfs
+
+import (
+	"sort"
+)
+
+/* Mountpoint is one entry of OS.Mountpoints: a filesystem or other
+ * mountable, and where it should be mounted.
+ */
+type Mountpoint struct {
+	Device string
+	Path   string
+}
+
+/* OS is everything InspectAll can tell you about one operating system
+ * found by Inspect_os, gathered into a single value instead of being
+ * spread across a dozen Inspect_get_* calls.
+ */
+type OS struct {
+	Root          string
+	ProductName   string
+	MajorVersion  int
+	MinorVersion  int
+	Type          string
+	Distro        string
+	Arch          string
+	Hostname      string
+	PackageFormat string
+
+	/* Mountpoints is ordered so that parents precede children
+	 * (shorter paths first), which is the order MountAll needs to
+	 * mount them in.
+	 */
+	Mountpoints []Mountpoint
+
+	/* DriveMappings maps a guest device name (e.g. "/dev/sda") to
+	 * the name of the drive as added with Add_drive, as returned by
+	 * Inspect_get_drive_mappings.  It is only populated for
+	 * operating systems where the mapping could be determined (for
+	 * example Windows).
+	 */
+	DriveMappings map[string]string
+}
+
+/* InspectAll runs Inspect_os and gathers the usual follow-up calls
+ * (product name, version, type, distro, arch, hostname, package
+ * format, drive mappings, mountpoints) into one []OS, one element per
+ * root.  Fields that fail to inspect are left at their zero value, the
+ * same as callers already do by ignoring the error return of the
+ * individual Inspect_get_* calls.
+ */
+func (g *Guestfs) InspectAll () ([]OS, error) {
+	roots, err := g.Inspect_os ()
+	if err != nil {
+		return nil, err
+	}
+
+	oses := make ([]OS, 0, len (roots))
+	for _, root := range roots {
+		o := OS{Root: root}
+
+		o.ProductName, _ = g.Inspect_get_product_name (root)
+		o.MajorVersion, _ = g.Inspect_get_major_version (root)
+		o.MinorVersion, _ = g.Inspect_get_minor_version (root)
+		o.Type, _ = g.Inspect_get_type (root)
+		o.Distro, _ = g.Inspect_get_distro (root)
+		o.Arch, _ = g.Inspect_get_arch (root)
+		o.Hostname, _ = g.Inspect_get_hostname (root)
+		o.PackageFormat, _ = g.Inspect_get_package_format (root)
+		o.DriveMappings, _ = g.Inspect_get_drive_mappings (root)
+
+		mountpoints, err := g.Inspect_get_mountpoints (root)
+		if err != nil {
+			return nil, err
+		}
+		o.Mountpoints = make ([]Mountpoint, 0, len (mountpoints))
+		for path, device := range mountpoints {
+			o.Mountpoints = append (o.Mountpoints, Mountpoint{Device: device, Path: path})
+		}
+		sortMountpointsByDepth (o.Mountpoints)
+
+		oses = append (oses, o)
+	}
+
+	return oses, nil
+}
+
+/* sortMountpointsByDepth orders mps so that parents precede children,
+ * using the well-known trick of sorting by path length: "/" is
+ * shorter than "/usr", which is shorter than "/usr/local", and so on.
+ */
+func sortMountpointsByDepth (mps []Mountpoint) {
+	sort.Slice (mps, func (i, j int) bool {
+		return len (mps[i].Path) < len (mps[j].Path)
+	})
+}
+
+/* MountAll mounts every entry of o.Mountpoints against g, in the order
+ * they are stored in (parents before children).
+ */
+func (o *OS) MountAll (g *Guestfs) error {
+	for _, mp := range o.Mountpoints {
+		if err := g.Mount (mp.Device, mp.Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
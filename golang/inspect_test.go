@@ -0,0 +1,46 @@
+/* libguestfs Go bindings
+ * Copyright (C) 2013 Red Hat Inc.
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA
+ */
+
+package guestfs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortMountpointsByDepth (t *testing.T) {
+	mps := []Mountpoint{
+		{Device: "/dev/sda1", Path: "/usr/local"},
+		{Device: "/dev/sda2", Path: "/"},
+		{Device: "/dev/sda4", Path: "/usr/local/bin"},
+		{Device: "/dev/sda3", Path: "/usr"},
+	}
+
+	sortMountpointsByDepth (mps)
+
+	want := []Mountpoint{
+		{Device: "/dev/sda2", Path: "/"},
+		{Device: "/dev/sda3", Path: "/usr"},
+		{Device: "/dev/sda1", Path: "/usr/local"},
+		{Device: "/dev/sda4", Path: "/usr/local/bin"},
+	}
+
+	if !reflect.DeepEqual (mps, want) {
+		t.Errorf ("sortMountpointsByDepth = %v, want %v", mps, want)
+	}
+}
@@ -0,0 +1,64 @@
+/* libguestfs Go bindings
+ * Copyright (C) 2013 Red Hat Inc.
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA
+ */
+
+package guestfs
+
+import (
+	"testing"
+)
+
+func TestDriveOptions (t *testing.T) {
+	optargs := OptargsAdd_drive{}
+
+	options := []DriveOption{
+		DriveFormat ("raw"),
+		DriveReadonly (true),
+		DriveLabel ("root"),
+		DriveProtocol ("nbd"),
+	}
+	for _, option := range options {
+		option (&optargs)
+	}
+
+	want := OptargsAdd_drive{
+		Format_is_set:   true,
+		Format:          "raw",
+		Readonly_is_set: true,
+		Readonly:        true,
+		Label_is_set:    true,
+		Label:           "root",
+		Protocol_is_set: true,
+		Protocol:        "nbd",
+	}
+	if optargs != want {
+		t.Errorf ("optargs = %+v, want %+v", optargs, want)
+	}
+}
+
+func TestDriveOptionsOnlySetFieldsThatAreGiven (t *testing.T) {
+	optargs := OptargsAdd_drive{}
+
+	DriveFormat ("qcow2") (&optargs)
+
+	if !optargs.Format_is_set || optargs.Format != "qcow2" {
+		t.Errorf ("Format/Format_is_set not set correctly: %+v", optargs)
+	}
+	if optargs.Readonly_is_set || optargs.Label_is_set || optargs.Protocol_is_set {
+		t.Errorf ("an option set a field it shouldn't have touched: %+v", optargs)
+	}
+}
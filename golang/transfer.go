@@ -0,0 +1,141 @@
+/* libguestfs Go bindings
+ * Copyright (C) 2013 Red Hat Inc.
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA
+ */
+
+package guestfs
+
+import (
+	"io"
+	"os"
+	"strconv"
+)
+
+/* Upload and Download only take a local path, because that is all
+ * that the generated FileIn/FileOut parameters give us: a filename to
+ * open.  To let a caller supply an io.Reader or io.Writer instead
+ * without staging the data on disk, UploadFrom and DownloadTo open a
+ * pipe and hand libguestfs the read (or write) end's /proc/self/fd
+ * path, while a goroutine copies between the write (or read) end and
+ * the caller's reader or writer.  Only Upload/Download and their
+ * *_offset forms have this so far; see the package comment.
+ *
+ * This was asked for as a generator/golang.ml change, recognising
+ * every FileIn/FileOut parameter and emitting a reader/writer form.
+ * generator/golang.ml is not in this tree, so only Upload/Download are
+ * covered here by hand; other FileIn/FileOut actions get no such form
+ * and will need the same hand-written treatment or the real generator
+ * support.
+ */
+
+/* UploadFrom uploads the data read from r to guestPath, without
+ * requiring it to exist as a file on the local filesystem first.
+ */
+func (g *Guestfs) UploadFrom (guestPath string, r io.Reader) error {
+	return copyReaderToPipe (r, func (fdPath string) error {
+		return g.Upload (fdPath, guestPath)
+	})
+}
+
+/* UploadOffsetFrom is like UploadFrom, but the data is written into
+ * guestPath starting at offset, as with upload-offset.
+ */
+func (g *Guestfs) UploadOffsetFrom (guestPath string, r io.Reader, offset int64) error {
+	return copyReaderToPipe (r, func (fdPath string) error {
+		return g.Upload_offset (fdPath, guestPath, offset)
+	})
+}
+
+/* DownloadTo downloads guestPath to w, without requiring a local file
+ * to download into first.
+ */
+func (g *Guestfs) DownloadTo (guestPath string, w io.Writer) error {
+	return copyPipeToWriter (w, func (fdPath string) error {
+		return g.Download (guestPath, fdPath)
+	})
+}
+
+/* DownloadOffsetTo is like DownloadTo, but only the size bytes of
+ * guestPath starting at offset are downloaded, as with
+ * download-offset.
+ */
+func (g *Guestfs) DownloadOffsetTo (guestPath string, w io.Writer, offset int64, size int64) error {
+	return copyPipeToWriter (w, func (fdPath string) error {
+		return g.Download_offset (guestPath, fdPath, offset, size)
+	})
+}
+
+/* copyReaderToPipe creates a pipe, copies r into its write end in the
+ * background, and runs call against the read end's /proc/self/fd
+ * path, so that the libguestfs call can be given a "filename" that is
+ * really r.  Used for uploads, where libguestfs reads from the path.
+ */
+func copyReaderToPipe (r io.Reader, call func (fdPath string) error) error {
+	pr, pw, err := os.Pipe ()
+	if err != nil {
+		return err
+	}
+
+	copyErr := make (chan error, 1)
+	go func () {
+		_, err := io.Copy (pw, r)
+		pw.Close ()
+		copyErr <- err
+	}()
+
+	err = call (procSelfFd (pr))
+	pr.Close ()
+
+	if copye := <-copyErr; err == nil {
+		err = copye
+	}
+	return err
+}
+
+/* copyPipeToWriter creates a pipe, runs call against the write end's
+ * /proc/self/fd path so that the libguestfs call can be given a
+ * "filename" that is really w, and copies whatever it writes into w in
+ * the background.  Used for downloads, where libguestfs writes to the
+ * path.
+ */
+func copyPipeToWriter (w io.Writer, call func (fdPath string) error) error {
+	pr, pw, err := os.Pipe ()
+	if err != nil {
+		return err
+	}
+
+	copyErr := make (chan error, 1)
+	go func () {
+		_, err := io.Copy (w, pr)
+		pr.Close ()
+		copyErr <- err
+	}()
+
+	err = call (procSelfFd (pw))
+	pw.Close ()
+
+	if copye := <-copyErr; err == nil {
+		err = copye
+	}
+	return err
+}
+
+/* procSelfFd returns a path that the daemon can open to read or write
+ * the other end of a pipe we hold open in this process.
+ */
+func procSelfFd (f *os.File) string {
+	return "/proc/self/fd/" + strconv.Itoa (int (f.Fd ()))
+}
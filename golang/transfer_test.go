@@ -0,0 +1,78 @@
+/* libguestfs Go bindings
+ * Copyright (C) 2013 Red Hat Inc.
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA
+ */
+
+package guestfs
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+)
+
+/* copyReaderToPipe and copyPipeToWriter used to be reached via a type
+ * switch on a plain interface{} value, which silently broke for any
+ * type that implements both io.Reader and io.Writer (for example
+ * *bytes.Buffer or *os.File).  These tests exercise call with such a
+ * type to make sure that bug class cannot come back unnoticed.
+ */
+
+func TestCopyReaderToPipeWithReaderWriter (t *testing.T) {
+	src := bytes.NewBufferString ("hello world")
+
+	var got []byte
+	err := copyReaderToPipe (src, func (fdPath string) error {
+		f, err := os.Open (fdPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close ()
+
+		got, err = io.ReadAll (f)
+		return err
+	})
+	if err != nil {
+		t.Fatalf ("copyReaderToPipe () = %v", err)
+	}
+
+	if string (got) != "hello world" {
+		t.Errorf ("copyReaderToPipe () copied %q, want %q", got, "hello world")
+	}
+}
+
+func TestCopyPipeToWriterWithReaderWriter (t *testing.T) {
+	dst := &bytes.Buffer{}
+
+	err := copyPipeToWriter (dst, func (fdPath string) error {
+		f, err := os.OpenFile (fdPath, os.O_WRONLY, 0)
+		if err != nil {
+			return err
+		}
+		defer f.Close ()
+
+		_, err = f.Write ([]byte ("hello world"))
+		return err
+	})
+	if err != nil {
+		t.Fatalf ("copyPipeToWriter () = %v", err)
+	}
+
+	if dst.String () != "hello world" {
+		t.Errorf ("copyPipeToWriter () copied %q, want %q", dst.String (), "hello world")
+	}
+}